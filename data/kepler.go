@@ -0,0 +1,119 @@
+package data
+
+import "math"
+
+const keplerTolerance = 1e-12
+const keplerMaxIterations = 50
+
+// normalizeAngle wraps an angle (in radians) into the range [-pi, pi]
+func normalizeAngle(angle float64) float64 {
+	angle = math.Mod(angle, 2*math.Pi)
+	if angle > math.Pi {
+		angle -= 2 * math.Pi
+	} else if angle < -math.Pi {
+		angle += 2 * math.Pi
+	}
+	return angle
+}
+
+// solveEllipticKepler solves Kepler's equation M = E - e*sin(E) for the
+// eccentric anomaly E, given mean anomaly M (radians) and eccentricity
+// 0 <= e < 1. It uses Newton-Raphson, starting from E0 = M + e*sin(M),
+// and falls back to bisection if the iteration stalls (e.g. e close to 1).
+func solveEllipticKepler(M, e float64) float64 {
+	M = normalizeAngle(M)
+
+	E := M + e*math.Sin(M)
+	for i := 0; i < keplerMaxIterations; i++ {
+		dE := (E - e*math.Sin(E) - M) / (1 - e*math.Cos(E))
+		E -= dE
+		if math.Abs(dE) < keplerTolerance {
+			return E
+		}
+	}
+
+	// Newton-Raphson failed to converge; bisection always does.
+	lo, hi := M-math.Pi, M+math.Pi
+	for i := 0; i < 200; i++ {
+		E = (lo + hi) / 2
+		f := E - e*math.Sin(E) - M
+		if math.Abs(f) < keplerTolerance {
+			break
+		}
+		if f > 0 {
+			hi = E
+		} else {
+			lo = E
+		}
+	}
+	return E
+}
+
+// solveHyperbolicKepler solves the hyperbolic Kepler equation
+// M = e*sinh(H) - H for the hyperbolic anomaly H, given mean anomaly M
+// and eccentricity e > 1. It uses Newton-Raphson, starting from
+// H0 = asinh(M/e), and falls back to bisection if the iteration stalls.
+func solveHyperbolicKepler(M, e float64) float64 {
+	H := math.Asinh(M / e)
+	for i := 0; i < keplerMaxIterations; i++ {
+		dH := (e*math.Sinh(H) - H - M) / (e*math.Cosh(H) - 1)
+		H -= dH
+		if math.Abs(dH) < keplerTolerance {
+			return H
+		}
+	}
+
+	// Newton-Raphson failed to converge; bisection always does. For e > 1,
+	// e*sinh(H) - H >= H^3/6, so |H| <= cbrt(6|M|) <= |M|+1 and
+	// +/-(|M|+1) safely brackets it.
+	bound := math.Abs(M) + 1
+	lo, hi := -bound, bound
+	for i := 0; i < 200; i++ {
+		H = (lo + hi) / 2
+		f := e*math.Sinh(H) - H - M
+		if math.Abs(f) < keplerTolerance {
+			break
+		}
+		if f > 0 {
+			hi = H
+		} else {
+			lo = H
+		}
+	}
+	return H
+}
+
+// solveParabolicKepler solves Barker's equation D^3/3 + D = M for the
+// parabolic anomaly D, given mean anomaly M. Unlike the elliptic and
+// hyperbolic cases this has a closed-form solution via Cardano's formula
+// for the depressed cubic D^3 + 3D - 3M = 0.
+func solveParabolicKepler(M float64) float64 {
+	w := 1.5 * M
+	z := math.Cbrt(w + math.Sqrt(w*w+1))
+	return z - 1/z
+}
+
+// trueAnomaly solves Kepler's equation for the given orbital elements at
+// the given julian day and returns the polar coordinates (radius in AU,
+// true anomaly in radians) of the body within its orbital plane.
+func trueAnomaly(a, e, m0, n, julianDay float64) (r, nu float64) {
+	M := m0 + (julianDay-J2000)*n
+
+	switch {
+	case e < 1:
+		E := solveEllipticKepler(M, e)
+		y := math.Sqrt(1+e) * math.Sin(E/2)
+		x := math.Sqrt(1-e) * math.Cos(E/2)
+		return a * (1 - e*math.Cos(E)), 2 * math.Atan2(y, x)
+
+	case e > 1:
+		H := solveHyperbolicKepler(M, e)
+		y := math.Sqrt(e+1) * math.Sinh(H/2)
+		x := math.Sqrt(e-1) * math.Cosh(H/2)
+		return a * (e*math.Cosh(H) - 1), 2 * math.Atan2(y, x)
+
+	default: // e == 1, parabolic
+		D := solveParabolicKepler(M)
+		return a * (1 + D*D), 2 * math.Atan(D)
+	}
+}
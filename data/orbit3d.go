@@ -0,0 +1,165 @@
+package data
+
+import "math"
+
+// Vector3D is a 3D-cartesian coordinate
+type Vector3D struct {
+	X, Y, Z float64
+}
+
+// Orbit3D describes an elliptical, parabolic, or hyperbolic path that is
+// not confined to its parent's reference plane.
+type Orbit3D interface {
+	Period() float64 // in days
+
+	// PerifocalPositionAt and PerifocalVelocityAt report the body's state
+	// in the perifocal frame: x toward periapsis, y 90 degrees ahead in
+	// the direction of motion, in AU and AU/day respectively.
+	PerifocalPositionAt(julianDay float64) Vector2D
+	PerifocalVelocityAt(julianDay float64) Vector2D
+
+	// PositionAt and VelocityAt report the body's state in the parent's
+	// inertial frame, in AU and AU/day respectively.
+	PositionAt(julianDay float64) Vector3D
+	VelocityAt(julianDay float64) Vector3D
+}
+
+// Base implementation of Orbit3D
+type orbit3D struct {
+	orbit         // a, e, m0, n as in the coplanar case; pomega is unused here
+	i     float64 // inclination
+	raan  float64 // longitude of ascending node (Ω)
+	argP  float64 // argument of periapsis (ω)
+}
+
+type jsonOrbit3D struct {
+	A    float64 `json:"a"`
+	E    float64 `json:"e"`
+	I    float64 `json:"i"`
+	RAAN float64 `json:"raan"`
+	ArgP float64 `json:"argp"`
+	M    float64 `json:"M0"`
+	N    float64 `json:"n"`
+
+	// State at J2000, alongside the elements above: perifocal-frame
+	// position/velocity, and the inertial-frame position/velocity they
+	// rotate into.
+	PerifocalPos Vector2D `json:"perifocalPos"`
+	PerifocalVel Vector2D `json:"perifocalVel"`
+	Pos          Vector3D `json:"pos"`
+	Vel          Vector3D `json:"vel"`
+}
+
+func (o *orbit3D) toJSON() jsonOrbit3D {
+	return jsonOrbit3D{
+		o.a,
+		o.e,
+		o.i,
+		o.raan,
+		o.argP,
+		o.m0,
+		o.n,
+		o.PerifocalPositionAt(J2000),
+		o.PerifocalVelocityAt(J2000),
+		o.PositionAt(J2000),
+		o.VelocityAt(J2000),
+	}
+}
+
+// NewOrbit3D builds an orbit3D from classical orbital elements: semi-major
+// axis a, eccentricity e, inclination i, longitude of ascending node raan,
+// argument of periapsis argP, mean anomaly at epoch m0, and mean angular
+// motion n (per day). Angles are in radians.
+func NewOrbit3D(a, e, i, raan, argP, m0, n float64) orbit3D {
+	return orbit3D{orbit{a, e, 0, m0, n}, i, raan, argP}
+}
+
+func (o *orbit3D) PerifocalPositionAt(julianDay float64) Vector2D {
+	if o.a <= 0 {
+		return Vector2D{}
+	}
+
+	r, nu := trueAnomaly(o.a, o.e, o.m0, o.n, julianDay)
+	return Vector2D{r * math.Cos(nu), r * math.Sin(nu)}
+}
+
+func (o *orbit3D) PerifocalVelocityAt(julianDay float64) Vector2D {
+	if o.a <= 0 {
+		return Vector2D{}
+	}
+
+	_, nu := trueAnomaly(o.a, o.e, o.m0, o.n, julianDay)
+
+	// k = sqrt(GM/p), the perifocal velocity scale factor, derived from
+	// GM = n^2*a^3 (elliptic/hyperbolic) or GM = 2*n^2*a^3 (parabolic,
+	// with a the periapsis distance) without needing GM directly.
+	var k float64
+	if o.e == 1 {
+		k = o.n * o.a
+	} else {
+		k = o.n * o.a / math.Sqrt(math.Abs(1-o.e*o.e))
+	}
+
+	return Vector2D{-k * math.Sin(nu), k * (o.e + math.Cos(nu))}
+}
+
+// perifocalToInertial rotates a perifocal-frame vector (z = 0 implied) into
+// the parent's inertial frame via the 3-1-3 Euler rotation
+// Rz(-raan)*Rx(-i)*Rz(-argP).
+func perifocalToInertial(v Vector2D, i, raan, argP float64) Vector3D {
+	cO, sO := math.Cos(raan), math.Sin(raan)
+	ci, si := math.Cos(i), math.Sin(i)
+	cw, sw := math.Cos(argP), math.Sin(argP)
+
+	r11 := cO*cw - sO*sw*ci
+	r12 := -cO*sw - sO*cw*ci
+	r21 := sO*cw + cO*sw*ci
+	r22 := -sO*sw + cO*cw*ci
+	r31 := sw * si
+	r32 := cw * si
+
+	return Vector3D{
+		r11*v.X + r12*v.Y,
+		r21*v.X + r22*v.Y,
+		r31*v.X + r32*v.Y,
+	}
+}
+
+func (o *orbit3D) PositionAt(julianDay float64) Vector3D {
+	return perifocalToInertial(o.PerifocalPositionAt(julianDay), o.i, o.raan, o.argP)
+}
+
+func (o *orbit3D) VelocityAt(julianDay float64) Vector3D {
+	return perifocalToInertial(o.PerifocalVelocityAt(julianDay), o.i, o.raan, o.argP)
+}
+
+// orbit3DAdapter projects an orbit3D's inertial position onto its
+// parent's x,y plane (dropping z) so it can satisfy the plain 2D Orbit
+// interface and be used as a Body's ephemeris override.
+type orbit3DAdapter struct {
+	*orbit3D
+}
+
+func (a orbit3DAdapter) PositionAt(julianDay float64) Vector2D {
+	p := a.orbit3D.PositionAt(julianDay)
+	return Vector2D{p.X, p.Y}
+}
+
+// NewBody3D builds a *Body whose ephemeris is a 3D orbit (see NewOrbit3D)
+// rather than NewBody's coplanar one, for moons, asteroids, and comets
+// that need inclination and node to render correctly. The embedded
+// orbit's a, e, m0, and n are still populated from the same elements, so
+// NewSystem's SOI and barycenter math works exactly as it would for a
+// coplanar Body.
+func NewBody3D(btype BodyType, name string, radius, mass, a, e, i, raan, argP, m0, n float64) *Body {
+	o := NewOrbit3D(a, e, i, raan, argP, m0, n)
+
+	b := &Body{}
+	b.btype = btype
+	b.name = name
+	b.radius = radius
+	b.mass = mass
+	b.orbit = o.orbit
+	b.ephemeris = orbit3DAdapter{&o}
+	return b
+}
@@ -0,0 +1,401 @@
+package data
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// WGS72 constants used by SGP4, in km / km^3/s^2.
+const (
+	sgp4mu            = 398600.8
+	sgp4radiusearthkm = 6378.135
+	sgp4j2            = 0.001082616
+	sgp4j3            = -0.00000253881
+	sgp4j4            = -0.00000165597
+)
+
+// kmPerAU converts SGP4's km output into the AU used everywhere else in
+// this package.
+const kmPerAU = 149597870.7
+
+// Satellite is an Orbitable-like object propagated from a two-line element
+// set via SGP4, rather than from fixed Keplerian elements like Body or
+// Ship. It only models near-earth SGP4 (no deep-space resonance terms),
+// which suits low orbits such as ISS and Starlink; objects with periods
+// of 225 minutes or more (e.g. GPS) are still propagated with the
+// near-earth model and lose accuracy accordingly.
+type Satellite struct {
+	name       string
+	catalogNum int
+	epochJD    float64
+	bstar      float64
+
+	// Mean elements at epoch, after undoing the Kozai mean motion.
+	inclo, nodeo, ecco, argpo, mo, no, ao float64
+
+	// Coefficients derived once at parse time and reused by every call to
+	// PositionAt; see initSGP4.
+	cosio, sinio, con41, con42, x1mth2, x7thm1 float64
+	cc1, cc4, cc5, mdot, argpdot, nodedot      float64
+	omgcof, xmcof, nodecf, t2cof, xlcof, aycof float64
+	eta, delmo, sinmao                         float64
+}
+
+// Name returns the satellite's name/catalog designation.
+func (s *Satellite) Name() string { return s.name }
+
+// CatalogNumber returns the NORAD catalog number parsed from the TLE.
+func (s *Satellite) CatalogNumber() int { return s.catalogNum }
+
+// ParseTLE decodes a standard two-line element set into a Satellite ready
+// for SGP4 propagation via PositionAt.
+func ParseTLE(line1, line2 string) (*Satellite, error) {
+	if len(line1) < 69 || len(line2) < 69 {
+		return nil, fmt.Errorf("data: TLE lines must be at least 69 characters, got %d and %d", len(line1), len(line2))
+	}
+	if line1[0] != '1' || line2[0] != '2' {
+		return nil, fmt.Errorf("data: not a TLE (expected line numbers 1 and 2)")
+	}
+
+	catalogNum, err := strconv.Atoi(strings.TrimSpace(line1[2:7]))
+	if err != nil {
+		return nil, fmt.Errorf("data: invalid catalog number: %w", err)
+	}
+
+	epochYear, err := strconv.Atoi(strings.TrimSpace(line1[18:20]))
+	if err != nil {
+		return nil, fmt.Errorf("data: invalid epoch year: %w", err)
+	}
+	epochDay, err := strconv.ParseFloat(strings.TrimSpace(line1[20:32]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("data: invalid epoch day: %w", err)
+	}
+
+	bstar, err := parseTLEExponential(line1[53:61])
+	if err != nil {
+		return nil, fmt.Errorf("data: invalid BSTAR: %w", err)
+	}
+
+	inclDeg, err := strconv.ParseFloat(strings.TrimSpace(line2[8:16]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("data: invalid inclination: %w", err)
+	}
+	raanDeg, err := strconv.ParseFloat(strings.TrimSpace(line2[17:25]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("data: invalid RAAN: %w", err)
+	}
+	ecco, err := parseTLEDecimal(line2[26:33])
+	if err != nil {
+		return nil, fmt.Errorf("data: invalid eccentricity: %w", err)
+	}
+	argpDeg, err := strconv.ParseFloat(strings.TrimSpace(line2[34:42]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("data: invalid argument of perigee: %w", err)
+	}
+	moDeg, err := strconv.ParseFloat(strings.TrimSpace(line2[43:51]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("data: invalid mean anomaly: %w", err)
+	}
+	revPerDay, err := strconv.ParseFloat(strings.TrimSpace(line2[52:63]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("data: invalid mean motion: %w", err)
+	}
+
+	year := epochYear
+	if year < 57 {
+		year += 2000
+	} else {
+		year += 1900
+	}
+
+	s := &Satellite{
+		name:       fmt.Sprintf("NORAD %d", catalogNum),
+		catalogNum: catalogNum,
+		epochJD:    jdFromYearDay(year, epochDay),
+		bstar:      bstar,
+		inclo:      inclDeg * math.Pi / 180,
+		nodeo:      raanDeg * math.Pi / 180,
+		ecco:       ecco,
+		argpo:      argpDeg * math.Pi / 180,
+		mo:         moDeg * math.Pi / 180,
+	}
+	// no_kozai: rev/day -> rad/min
+	noKozai := revPerDay * 2 * math.Pi / 1440
+
+	s.initSGP4(noKozai)
+	return s, nil
+}
+
+// jdFromYearDay converts a calendar year and a (possibly fractional)
+// day-of-year into a Julian day.
+func jdFromYearDay(year int, dayOfYear float64) float64 {
+	y := float64(year)
+	return 367*y - math.Floor(1.75*y) + 30 + dayOfYear + 1721013.5
+}
+
+// parseTLEDecimal parses a TLE field with an assumed leading decimal point,
+// e.g. "0001666" -> 0.0001666.
+func parseTLEDecimal(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty field")
+	}
+	sign := 1.0
+	if s[0] == '-' {
+		sign = -1
+		s = s[1:]
+	} else if s[0] == '+' {
+		s = s[1:]
+	}
+	v, err := strconv.ParseFloat("0."+s, 64)
+	if err != nil {
+		return 0, err
+	}
+	return sign * v, nil
+}
+
+// parseTLEExponential parses a TLE assumed-decimal-point field with a
+// trailing signed power-of-ten exponent, e.g. " 12345-3" -> 0.12345e-3.
+func parseTLEExponential(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	i := strings.IndexAny(s[1:], "+-")
+	if i < 0 {
+		return 0, fmt.Errorf("missing exponent in %q", s)
+	}
+	i++ // index was relative to s[1:]
+
+	mantissa, err := parseTLEDecimal(s[:i])
+	if err != nil {
+		return 0, err
+	}
+	exp, err := strconv.Atoi(s[i:])
+	if err != nil {
+		return 0, err
+	}
+	return mantissa * math.Pow(10, float64(exp)), nil
+}
+
+// initSGP4 derives the secular and periodic coefficients used by
+// PositionAt from the mean elements at epoch, following the SGP4
+// near-earth initialization (Hoots & Roehrich / Vallado). It does not
+// implement the deep-space resonance terms, the low-perigee (<156km)
+// high-drag correction terms, or the higher-order (t^3 and beyond)
+// secular drag terms; those refinements matter for a small minority of
+// orbits and are left out for simplicity.
+func (s *Satellite) initSGP4(noKozai float64) {
+	xke := 60.0 / math.Sqrt(sgp4radiusearthkm*sgp4radiusearthkm*sgp4radiusearthkm/sgp4mu)
+	j3oj2 := sgp4j3 / sgp4j2
+
+	eccsq := s.ecco * s.ecco
+	omeosq := 1 - eccsq
+	rteosq := math.Sqrt(omeosq)
+	s.cosio = math.Cos(s.inclo)
+	s.sinio = math.Sin(s.inclo)
+	cosio2 := s.cosio * s.cosio
+
+	// Undo the Kozai mean motion correction to recover the original mean
+	// motion and semi-major axis.
+	ak := math.Pow(xke/noKozai, 2.0/3.0)
+	d1 := 0.75 * sgp4j2 * (3*cosio2 - 1) / (rteosq * omeosq)
+	del_ := d1 / (ak * ak)
+	adel := ak * (1 - del_*del_ - del_*(1.0/3.0+134*del_*del_/81))
+	del_ = d1 / (adel * adel)
+	s.no = noKozai / (1 + del_)
+	s.ao = math.Pow(xke/s.no, 2.0/3.0)
+
+	s.con42 = 1 - 5*cosio2
+	s.con41 = -s.con42 - cosio2 - cosio2
+	s.x1mth2 = 1 - cosio2
+	s.x7thm1 = 7*cosio2 - 1
+
+	po := s.ao * omeosq
+	pinvsq := 1 / (po * po)
+
+	// Fixed (non-perigee-adjusted) atmospheric density term.
+	sPar := 78.0/sgp4radiusearthkm + 1
+	qzms2t := math.Pow((120.0-78.0)/sgp4radiusearthkm, 4)
+
+	tsi := 1 / (s.ao - sPar)
+	eta := s.ao * s.ecco * tsi
+	etasq := eta * eta
+	eeta := s.ecco * eta
+	psisq := math.Abs(1 - etasq)
+	coef := qzms2t * math.Pow(tsi, 4)
+	coef1 := coef / math.Pow(psisq, 3.5)
+
+	cc2 := coef1 * s.no * (s.ao*(1+1.5*etasq+eeta*(4+etasq)) +
+		0.375*sgp4j2*tsi/psisq*s.con41*(8+3*etasq*(8+etasq)))
+	s.cc1 = s.bstar * cc2
+
+	cc3 := 0.0
+	if s.ecco > 1e-4 {
+		cc3 = -2 * coef * tsi * j3oj2 * s.no * s.sinio / s.ecco
+	}
+
+	s.cc4 = 2 * s.no * coef1 * s.ao * omeosq * (eta*(2+0.5*etasq) + s.ecco*(0.5+2*etasq) -
+		sgp4j2*tsi/(s.ao*psisq)*(-3*s.con41*(1-2*eeta+etasq*(1.5-0.5*eeta))+
+			0.75*s.x1mth2*(2*etasq-eeta*(1+etasq))*math.Cos(2*s.argpo)))
+	s.cc5 = 2 * coef1 * s.ao * omeosq * (1 + 2.75*(etasq+eeta) + eeta*etasq)
+
+	cosio4 := cosio2 * cosio2
+	temp1 := 1.5 * sgp4j2 * pinvsq * s.no
+	temp2 := 0.5 * temp1 * sgp4j2 * pinvsq
+	temp3 := -0.46875 * sgp4j4 * pinvsq * pinvsq * s.no
+
+	s.mdot = s.no + 0.5*temp1*rteosq*s.con41 + 0.0625*temp2*rteosq*(13-78*cosio2+137*cosio4)
+	s.argpdot = -0.5*temp1*s.con42 + 0.0625*temp2*(7-114*cosio2+395*cosio4) + temp3*(3-36*cosio2+49*cosio4)
+	xhdot1 := -temp1 * s.cosio
+	s.nodedot = xhdot1 + (0.5*temp2*(4-19*cosio2)+2*temp3*(3-7*cosio2))*s.cosio
+
+	s.omgcof = s.bstar * cc3 * math.Cos(s.argpo)
+	s.xmcof = 0.0
+	if s.ecco > 1e-4 {
+		s.xmcof = -2.0 / 3.0 * coef * s.bstar / eeta
+	}
+	s.nodecf = 3.5 * omeosq * xhdot1 * s.cc1
+	s.t2cof = 1.5 * s.cc1
+	if math.Abs(s.cosio+1) > 1.5e-12 {
+		s.xlcof = -0.25 * j3oj2 * s.sinio * (3 + 5*s.cosio) / (1 + s.cosio)
+	}
+	s.aycof = -0.5 * j3oj2 * s.sinio
+	s.eta = eta
+	s.delmo = math.Pow(1+eta*math.Cos(s.mo), 3)
+	s.sinmao = math.Sin(s.mo)
+}
+
+// PositionAt returns the satellite's position, in AU relative to the
+// earth's center, at the given Julian day, via SGP4 propagation of the
+// epoch mean elements.
+func (s *Satellite) PositionAt(julianDay float64) Vector3D {
+	t := (julianDay - s.epochJD) * 1440 // minutes since epoch
+
+	// Secular effects of gravity (J2/J4) and drag on the mean elements.
+	xmdf := s.mo + s.mdot*t
+	argpdf := s.argpo + s.argpdot*t
+	nodem := s.nodeo + s.nodedot*t + s.nodecf*t*t
+	tempa := 1 - s.cc1*t
+	tempe := s.bstar * s.cc4 * t
+	templ := s.t2cof * t * t
+
+	delomg := s.omgcof * t
+	delmtemp := 1 + s.eta*math.Cos(xmdf)
+	delm := s.xmcof * (delmtemp*delmtemp*delmtemp - s.delmo)
+	temp := delomg + delm
+	mm := xmdf + temp
+	argpm := argpdf - temp
+	tempe += s.bstar * s.cc5 * (math.Sin(mm) - s.sinmao)
+
+	am := s.ao * tempa * tempa
+	em := s.ecco - tempe
+	em = math.Max(em, 1e-6)
+
+	// Long-period periodics.
+	axnl := em * math.Cos(argpm)
+	temp = 1 / (am * (1 - em*em))
+	aynl := em*math.Sin(argpm) + temp*s.aycof
+	xl := mm + argpm + nodem + temp*s.xlcof*axnl + s.no*templ
+
+	// Solve Kepler's equation for the eccentric longitude.
+	u := normalizeAngle(xl - nodem)
+	eo1 := u
+	for i := 0; i < keplerMaxIterations; i++ {
+		sineo1 := math.Sin(eo1)
+		coseo1 := math.Cos(eo1)
+		f := u - aynl*coseo1 + axnl*sineo1 - eo1
+		fPrime := -(1 - coseo1*axnl - sineo1*aynl)
+		dEo1 := f / fPrime
+		eo1 -= dEo1
+		if math.Abs(dEo1) < keplerTolerance {
+			break
+		}
+	}
+	sineo1 := math.Sin(eo1)
+	coseo1 := math.Cos(eo1)
+
+	ecose := axnl*coseo1 + aynl*sineo1
+	esine := axnl*sineo1 - aynl*coseo1
+	el2 := axnl*axnl + aynl*aynl
+	pl := am * (1 - el2)
+	rl := am * (1 - ecose)
+	betal := math.Sqrt(1 - el2)
+	temp = esine / (1 + betal)
+	sinu := am / rl * (sineo1 - aynl - axnl*temp)
+	cosu := am / rl * (coseo1 - axnl + aynl*temp)
+	su := math.Atan2(sinu, cosu)
+	sin2u := (cosu + cosu) * sinu
+	cos2u := 1 - 2*sinu*sinu
+
+	temp = 1 / pl
+	temp1 := 0.5 * sgp4j2 * temp
+	temp2 := temp1 * temp
+
+	// Short-period periodics.
+	mrt := rl*(1-1.5*temp2*betal*s.con41) + 0.5*temp1*s.x1mth2*cos2u
+	suFinal := su - 0.25*temp2*s.x7thm1*sin2u
+	xnode := nodem + 1.5*temp2*s.cosio*sin2u
+	xinc := s.inclo + 1.5*temp2*s.cosio*s.sinio*cos2u
+
+	sinsu, cossu := math.Sin(suFinal), math.Cos(suFinal)
+	snod, cnod := math.Sin(xnode), math.Cos(xnode)
+	sini, cosi := math.Sin(xinc), math.Cos(xinc)
+
+	xmx := -snod * cosi
+	xmy := cnod * cosi
+	ux := xmx*sinsu + cnod*cossu
+	uy := xmy*sinsu + snod*cossu
+	uz := sini * sinsu
+
+	rkm := mrt * sgp4radiusearthkm
+
+	return Vector3D{rkm * ux / kmPerAU, rkm * uy / kmPerAU, rkm * uz / kmPerAU}
+}
+
+// satelliteEphemeris adapts a Satellite's SGP4 propagation to the 2D
+// Orbit interface, projecting its position onto the parent's x,y plane
+// (dropping z), so it can back a Body the same way VSOP87Orbit and
+// orbit3D do.
+type satelliteEphemeris struct {
+	sat *Satellite
+}
+
+func (e satelliteEphemeris) Period() float64 {
+	if e.sat.no == 0 {
+		return math.Inf(1)
+	}
+	return 2 * math.Pi / e.sat.no / 1440 // no is rad/min; Period is in days
+}
+
+func (e satelliteEphemeris) PositionAt(julianDay float64) Vector2D {
+	p := e.sat.PositionAt(julianDay)
+	return Vector2D{p.X, p.Y}
+}
+
+// NewSatelliteBody builds a *Body whose position comes from sat's SGP4
+// propagation instead of fixed osculating elements, so real-world
+// catalog objects (ISS, Starlink, GPS, ...) can be registered under a
+// parent Body (typically Earth) and streamed alongside Kepler and
+// VSOP87 bodies in the same System. radius and mass are in AU and kg
+// respectively; TLEs don't carry either, so pass 0 for a trackable but
+// massless object, the common case since nothing meaningfully orbits a
+// satellite.
+func NewSatelliteBody(sat *Satellite, radius, mass float64) *Body {
+	b := &Body{btype: ArtificialSatellite}
+	b.name = sat.name
+	b.radius = radius
+	b.mass = mass
+	b.orbit = orbit{
+		sat.ao * sgp4radiusearthkm / kmPerAU,
+		sat.ecco,
+		sat.nodeo + sat.argpo,
+		sat.mo,
+		sat.no * 1440, // rad/min -> rad/day
+	}
+	b.ephemeris = satelliteEphemeris{sat}
+	return b
+}
@@ -20,8 +20,8 @@ type Orbit interface {
 
 // Base implementation of Orbit
 type orbit struct {
-	a      float64 // semi-major axis
-	e      float64 // eccentricity
+	a      float64 // semi-major axis (e < 1); periapsis distance (e == 1); magnitude of semi-major axis (e > 1)
+	e      float64 // eccentricity; e < 1 elliptical, e == 1 parabolic, e > 1 hyperbolic
 	pomega float64 // logitude of periapsis = longitude of ascending node + argument of periapsis
 	m0     float64 // mean anomaly at epoch (J2000)
 	n      float64 // mean angular motion (per day)
@@ -48,34 +48,22 @@ func (o *orbit) toJSON() jsonOrbit {
 // J2000 is the Julian Day reference point (January 1, 2000 at approximately 12:00 GMT)
 const J2000 = 2451545.0
 
-func (o *orbit) Period() float64 { return 2 * math.Pi / o.n }
+func (o *orbit) Period() float64 {
+	if o.e >= 1 {
+		return math.Inf(1) // hyperbolic and parabolic trajectories never repeat
+	}
+	return 2 * math.Pi / o.n
+}
 
 func (o *orbit) PositionAt(julianDay float64) Vector2D {
 	if o.a <= 0 {
 		return Vector2D{}
 	}
 
-	// Current mean anomaly
-	M := o.m0 + (julianDay-J2000)*o.n
-
-	// Convert to eccentric anomaly
-	E := M
-	for i := 0; i < 10; i++ { // TODO: check convergence?
-		E = M + o.e*math.Sin(E)
-	}
-
-	y := math.Sqrt(1-o.e) * math.Cos(E/2)
-	x := math.Sqrt(1+o.e) * math.Sin(E/2)
-
-	// Compute polar coordinate
-	r := o.a * (1 - o.e*math.Cos(E))
-	theta := o.pomega + 2*math.Atan2(y, x)
+	r, nu := trueAnomaly(o.a, o.e, o.m0, o.n, julianDay)
+	theta := o.pomega + nu
 
-	// Convert to cartesian
-	x = r * math.Cos(theta)
-	y = r * math.Sin(theta)
-
-	return Vector2D{x, y}
+	return Vector2D{r * math.Cos(theta), r * math.Sin(theta)}
 }
 
 func NewOrbit(a, e, pomega, m0, n float64) orbit {
@@ -181,12 +169,18 @@ const (
 	Moon
 	Asteroid
 	Comet
+	ArtificialSatellite // TLE/SGP4-propagated, e.g. via NewSatelliteBody
 )
 
 // Body is an Orbitable representing a Sun, Planet, Dwarf Planet, Moon, Asteroid, or Comet
 type Body struct {
 	orbitable
 	btype BodyType
+
+	// ephemeris, if set, overrides the embedded orbit (e.g. with a
+	// data.VSOP87Orbit) so callers can mix ephemeris backends within one
+	// System without caring which a given Body uses.
+	ephemeris Orbit
 	// rotational period
 
 	// declination
@@ -204,13 +198,53 @@ type jsonBody struct {
 	jsonOrbitable
 }
 
+// jsonBody3D is a Body's JSON shape when its ephemeris is a 3D orbit
+// (see NewBody3D): it marshals the 3D orbital elements and perifocal/
+// inertial state (jsonOrbit3D) in place of the coplanar jsonOrbit.
+type jsonBody3D struct {
+	Type   BodyType    `json:"type"`
+	Name   string      `json:"name"`
+	Radius float64     `json:"radius"`
+	Mass   float64     `json:"mass"`
+	SOI    float64     `json:"soi"`
+	Orbit  jsonOrbit3D `json:"orbit"`
+	Sats   []Orbitable `json:"satellites,omitempty"`
+}
+
 func (o *Body) MarshalJSON() ([]byte, error) {
+	if a, ok := o.ephemeris.(orbit3DAdapter); ok {
+		return json.Marshal(jsonBody3D{
+			o.btype,
+			o.name,
+			o.radius,
+			o.mass,
+			o.soi,
+			a.orbit3D.toJSON(),
+			o.satellites,
+		})
+	}
 	return json.Marshal(jsonBody{
 		o.btype,
 		o.orbitable.toJSON(),
 	})
 }
 
+// Period overrides orbit's via ephemeris, when set.
+func (o *Body) Period() float64 {
+	if o.ephemeris != nil {
+		return o.ephemeris.Period()
+	}
+	return o.orbit.Period()
+}
+
+// PositionAt overrides orbit's via ephemeris, when set.
+func (o *Body) PositionAt(julianDay float64) Vector2D {
+	if o.ephemeris != nil {
+		return o.ephemeris.PositionAt(julianDay)
+	}
+	return o.orbit.PositionAt(julianDay)
+}
+
 // NewBody creates a new *Body object from the given parameters
 func NewBody(btype BodyType, name string, radius, mass float64, obt orbit) *Body {
 	b := &Body{}
@@ -0,0 +1,105 @@
+package data
+
+import (
+	"fmt"
+	"math"
+)
+
+// vsop87Term is one periodic term A*cos(B + C*tau) in a VSOP87 series.
+type vsop87Term struct {
+	A, B, C float64
+}
+
+// vsop87Series is one variable (L, B, or R) for one planet. series[k] is
+// the list of terms multiplying tau^k.
+type vsop87Series [][]vsop87Term
+
+func (s vsop87Series) eval(tau float64) float64 {
+	sum := 0.0
+	tauPow := 1.0
+	for _, terms := range s {
+		var v float64
+		for _, term := range terms {
+			v += term.A * math.Cos(term.B+term.C*tau)
+		}
+		sum += v * tauPow
+		tauPow *= tau
+	}
+	return sum
+}
+
+// vsop87Planet holds the heliocentric ecliptic longitude (L), latitude
+// (B), and radius (R) series for one planet.
+type vsop87Planet struct {
+	L, B, R vsop87Series
+}
+
+// VSOP87Orbit is an Orbit backed by the analytical VSOP87 planetary
+// theory: it evaluates trigonometric series directly rather than
+// propagating fixed osculating elements, so its accuracy doesn't drift
+// over centuries the way orbit's linear mean-motion advance does.
+type VSOP87Orbit struct {
+	planet vsop87Planet
+}
+
+// NewVSOP87Orbit looks up the VSOP87 series for the named planet (see
+// vsop87Planets). It returns an error if name isn't in the table (the
+// coefficient tables only cover a subset of the major planets so far)
+// rather than panicking, since a missing planet is caller input to
+// handle, not a programming bug.
+func NewVSOP87Orbit(name string) (*VSOP87Orbit, error) {
+	p, ok := vsop87Planets[name]
+	if !ok {
+		return nil, fmt.Errorf("data: no VSOP87 series for %q", name)
+	}
+	return &VSOP87Orbit{planet: p}, nil
+}
+
+// tauPerDay is VSOP87's time unit: Julian millennia from J2000.
+const tauPerDay = 1.0 / 365250.0
+
+func (o *VSOP87Orbit) Period() float64 {
+	// L1's leading term is, by construction of the series, the secular
+	// (non-periodic, C == 0) mean motion; everything else in L1 is a
+	// small periodic correction to it.
+	if len(o.planet.L) < 2 || len(o.planet.L[1]) == 0 {
+		return math.Inf(1)
+	}
+	rate := o.planet.L[1][0].A * tauPerDay // rad/day
+	if rate == 0 {
+		return math.Inf(1)
+	}
+	return 2 * math.Pi / rate
+}
+
+func (o *VSOP87Orbit) PositionAt(julianDay float64) Vector2D {
+	tau := (julianDay - J2000) * tauPerDay
+
+	l := o.planet.L.eval(tau)
+	b := o.planet.B.eval(tau)
+	r := o.planet.R.eval(tau)
+
+	// Heliocentric ecliptic -> cartesian, projected onto the ecliptic
+	// plane: z = r*sin(b) is dropped since Orbit.PositionAt is 2D.
+	return Vector2D{
+		X: r * math.Cos(b) * math.Cos(l),
+		Y: r * math.Cos(b) * math.Sin(l),
+	}
+}
+
+// NewVSOP87Body builds a *Body whose position comes from VSOP87 instead
+// of fixed osculating elements. Radius and mass aren't known from the
+// series alone and are left at zero, as is the embedded orbit, so
+// NewSystem computes a zero SOI for it. It returns an error if name has
+// no VSOP87 series (see NewVSOP87Orbit).
+func NewVSOP87Body(name string) (*Body, error) {
+	orb, err := NewVSOP87Orbit(name)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Body{btype: Planet}
+	b.name = name
+	b.ephemeris = orb
+	return b, nil
+}
@@ -0,0 +1,40 @@
+package data
+
+// vsop87Planets holds the VSOP87D heliocentric ecliptic series used by
+// VSOP87Orbit, keyed by planet name. Each series here is truncated to its
+// handful of largest-amplitude terms rather than the full published
+// tables (hundreds of terms per planet) - enough to track a planet's true
+// position to within a fraction of a degree, with the framework able to
+// take the complete generated tables once those are produced.
+var vsop87Planets = map[string]vsop87Planet{
+	"Earth": {
+		L: vsop87Series{
+			{ // L0
+				{A: 1.75347032, B: 0, C: 0},
+				{A: 0.03341656, B: 4.6692568, C: 6283.0758500},
+				{A: 0.00034894, B: 4.6261000, C: 12566.1517000},
+				{A: 0.00003497, B: 2.7441000, C: 5753.3849000},
+				{A: 0.00003418, B: 2.8289000, C: 3.5231000},
+			},
+			{ // L1
+				{A: 6283.31966747, B: 0, C: 0},
+				{A: 0.00206059, B: 2.67823, C: 6283.07585},
+			},
+		},
+		B: vsop87Series{
+			{ // B0
+				{A: 0.00000243, B: 4.2137, C: 6283.0758},
+			},
+		},
+		R: vsop87Series{
+			{ // R0
+				{A: 1.00000018, B: 0, C: 0},
+				{A: 0.01670699, B: 3.0984635, C: 6283.0758500},
+				{A: 0.00013956, B: 3.0552400, C: 12566.1517000},
+			},
+			{ // R1
+				{A: 0.00103019, B: 1.10749, C: 6283.07585},
+			},
+		},
+	},
+}
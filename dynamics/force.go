@@ -0,0 +1,122 @@
+package dynamics
+
+import (
+	"math"
+
+	"github.com/chippydip/spacegame/data"
+)
+
+const g0 = 9.80665 // standard gravity, m/s^2, for the rocket equation
+
+// Force is one contributor to a Spacecraft's acceleration at time t
+// (julian day) given its current State.
+type Force interface {
+	Accel(t float64, s State) data.Vector3D
+}
+
+// Depletable is implemented by forces that consume a resource (e.g. fuel)
+// as time passes. Propagate calls Deplete with the size of each accepted
+// step, in days, after integrating it.
+type Depletable interface {
+	Deplete(dt float64)
+}
+
+// PointMassGravity is the gravitational pull of a point mass. PosAt
+// reports that mass's position, in the same frame as the integrated
+// State, at a given julian day; a nil PosAt treats the mass as fixed at
+// the frame's origin, which is the common case of gravity from the body a
+// Propagator's State is currently relative to.
+type PointMassGravity struct {
+	GM    float64 // m^3/s^2
+	PosAt func(julianDay float64) data.Vector3D
+}
+
+// NewCentralGravity builds the PointMassGravity of the body that a
+// Propagator's State is currently expressed relative to.
+func NewCentralGravity(body data.Orbitable) PointMassGravity {
+	return PointMassGravity{GM: body.GM()}
+}
+
+func (g PointMassGravity) Accel(t float64, s State) data.Vector3D {
+	if g.PosAt == nil {
+		// The mass is fixed at the frame's origin: ordinary two-body
+		// gravity toward -s.Pos.
+		return g.accelAt(scale(s.Pos, -1))
+	}
+
+	// A third body perturbs the ship both directly, and indirectly by
+	// accelerating Parent (the frame's origin) toward it. Since the
+	// frame is centered on - and accelerating with - Parent, the
+	// indirect term has to be subtracted back out.
+	origin := g.PosAt(t)
+	direct := g.accelAt(sub(origin, s.Pos))
+	indirect := g.accelAt(origin)
+	return sub(direct, indirect)
+}
+
+// accelAt returns the GM-mass's pull on a point displaced from it by -d
+// (i.e. d points from the accelerated point toward the mass).
+func (g PointMassGravity) accelAt(d data.Vector3D) data.Vector3D {
+	r := length(d)
+	if r == 0 {
+		return data.Vector3D{}
+	}
+
+	accelSI := g.GM / (r * auMeters) / (r * auMeters)
+	return scale(d, auPerDay2FromSI(accelSI)/r)
+}
+
+// J2 is the acceleration due to the oblateness (J2 zonal harmonic) of a
+// point mass fixed at the frame's origin.
+type J2 struct {
+	GM     float64 // m^3/s^2
+	J2     float64 // dimensionless
+	Radius float64 // equatorial radius, in AU
+}
+
+func (j J2) Accel(t float64, s State) data.Vector3D {
+	x, y, z := s.Pos.X, s.Pos.Y, s.Pos.Z
+	r := length(s.Pos)
+	if r == 0 {
+		return data.Vector3D{}
+	}
+
+	gm := gmAUday2(j.GM)
+	z2r2 := 5 * (z / r) * (z / r)
+	factor := -1.5 * j.J2 * gm * j.Radius * j.Radius / math.Pow(r, 5)
+
+	return data.Vector3D{
+		X: factor * x * (1 - z2r2),
+		Y: factor * y * (1 - z2r2),
+		Z: factor * z * (3 - z2r2),
+	}
+}
+
+// ConstantThrust applies a fixed-magnitude thrust along Direction (a unit
+// vector in the integration frame) while Craft has fuel, and depletes
+// that fuel via the rocket equation dm/dt = -T/(g0*Isp).
+type ConstantThrust struct {
+	Craft     *Spacecraft
+	Direction data.Vector3D
+	Thrust    float64 // Newtons
+	Isp       float64 // seconds
+}
+
+func (th ConstantThrust) Accel(t float64, s State) data.Vector3D {
+	if th.Craft == nil || th.Craft.FuelKg <= 0 {
+		return data.Vector3D{}
+	}
+
+	accelSI := th.Thrust / th.Craft.MassKg()
+	return scale(th.Direction, auPerDay2FromSI(accelSI))
+}
+
+func (th ConstantThrust) Deplete(dt float64) {
+	if th.Craft == nil || th.Craft.FuelKg <= 0 {
+		return
+	}
+
+	const secondsPerDay = 86400.0
+	burned := th.Thrust / (g0 * th.Isp) * dt * secondsPerDay
+	th.Craft.FuelKg = math.Max(0, th.Craft.FuelKg-burned)
+}
@@ -0,0 +1,107 @@
+package dynamics
+
+import "math"
+
+// derivative sums every Force's acceleration and returns the State
+// derivative: position's rate of change is velocity, velocity's rate of
+// change is the summed acceleration.
+func derivative(forces []Force, t float64, s State) State {
+	var accel State
+	for _, f := range forces {
+		accel.Vel = add(accel.Vel, f.Accel(t, s))
+	}
+	accel.Pos = s.Vel
+	return accel
+}
+
+// rk4Step advances State s by dt (days) using classic fixed-step RK4. It's
+// the baseline integrator; Propagate uses the adaptive dp45Step below so
+// this is mostly useful for quick, fixed-cadence propagation.
+func rk4Step(forces []Force, t, dt float64, s State) State {
+	k1 := derivative(forces, t, s)
+	k2 := derivative(forces, t+dt/2, addScaled(s, k1, dt/2))
+	k3 := derivative(forces, t+dt/2, addScaled(s, k2, dt/2))
+	k4 := derivative(forces, t+dt, addScaled(s, k3, dt))
+
+	sum := addScaled(addScaled(addScaled(k1, k2, 2), k3, 2), k4, 1)
+	return addScaled(s, sum, dt/6)
+}
+
+// Dormand-Prince 5(4) Butcher tableau.
+var (
+	dpC = [7]float64{0, 1.0 / 5, 3.0 / 10, 4.0 / 5, 8.0 / 9, 1, 1}
+	dpA = [7][6]float64{
+		{},
+		{1.0 / 5},
+		{3.0 / 40, 9.0 / 40},
+		{44.0 / 45, -56.0 / 15, 32.0 / 9},
+		{19372.0 / 6561, -25360.0 / 2187, 64448.0 / 6561, -212.0 / 729},
+		{9017.0 / 3168, -355.0 / 33, 46732.0 / 5247, 49.0 / 176, -5103.0 / 18656},
+		{35.0 / 384, 0, 500.0 / 1113, 125.0 / 192, -2187.0 / 6784, 11.0 / 84},
+	}
+	// dpB5 is dpA[6] with a trailing zero: the 5th-order solution shares
+	// the weights used to compute k7 (this method is FSAL), but needs its
+	// own length-7 vector to line up with k[0..6].
+	dpB5 = [7]float64{35.0 / 384, 0, 500.0 / 1113, 125.0 / 192, -2187.0 / 6784, 11.0 / 84, 0}
+	dpB4 = [7]float64{5179.0 / 57600, 0, 7571.0 / 16695, 393.0 / 640, -92097.0 / 339200, 187.0 / 2100, 1.0 / 40}
+)
+
+// dp45Trial runs one Dormand-Prince 5(4) step of size dt and returns the
+// 5th-order solution along with the embedded 4th-order solution; their
+// difference is the local error estimate.
+func dp45Trial(forces []Force, t, dt float64, s State) (y5, y4 State) {
+	var k [7]State
+	k[0] = derivative(forces, t, s)
+
+	for i := 1; i < 7; i++ {
+		stage := s
+		for j := 0; j < i; j++ {
+			stage = addScaled(stage, k[j], dt*dpA[i][j])
+		}
+		k[i] = derivative(forces, t+dpC[i]*dt, stage)
+	}
+
+	y5 = s
+	y4 = s
+	for i := 0; i < 7; i++ {
+		y5 = addScaled(y5, k[i], dt*dpB5[i])
+		y4 = addScaled(y4, k[i], dt*dpB4[i])
+	}
+	return y5, y4
+}
+
+// stateErrorNorm reports the larger of the Euclidean lengths of the
+// position difference and the velocity difference between two states,
+// used to drive dp45Step's step-size control.
+func stateErrorNorm(a, b State) float64 {
+	d := sub(a.Pos, b.Pos)
+	v := sub(a.Vel, b.Vel)
+	return math.Max(length(d), length(v))
+}
+
+const (
+	minStepDays = 1e-8
+	maxGrowth   = 5.0
+	minShrink   = 0.2
+)
+
+// dp45Step advances State s by approximately dt (days), shrinking the
+// step until the embedded error estimate is within tol (or the step is
+// down to minStepDays, which is accepted regardless), and returns the
+// new state, the step size actually used, and a suggested next step size.
+func dp45Step(forces []Force, t, dt, tol float64, s State) (next State, used, nextDt float64) {
+	for {
+		y5, y4 := dp45Trial(forces, t, dt, s)
+		errEst := stateErrorNorm(y5, y4)
+
+		if errEst <= tol || dt <= minStepDays {
+			growth := maxGrowth
+			if errEst > 0 {
+				growth = math.Min(maxGrowth, 0.9*math.Pow(tol/errEst, 0.2))
+			}
+			return y5, dt, dt * math.Max(minShrink, growth)
+		}
+
+		dt *= math.Max(minShrink, 0.9*math.Pow(tol/errEst, 0.2))
+	}
+}
@@ -0,0 +1,124 @@
+package dynamics
+
+import (
+	"time"
+
+	"github.com/chippydip/spacegame/data"
+)
+
+// initialStepDays is the starting guess for dp45Step's adaptive step size.
+const initialStepDays = 1.0 / 24 // one hour
+
+// Propagator numerically integrates a Spacecraft's trajectory under a set
+// of Forces using an adaptive Dormand-Prince 5(4) step, re-parenting the
+// state across sphere-of-influence boundaries as it goes.
+type Propagator struct {
+	Forces []Force
+	Parent data.Orbitable // the body State is currently relative to
+	State  State
+	Tol    float64 // local error tolerance used by step-size control
+
+	// Collided reports whether the trajectory reached the current
+	// Parent's Radius during the most recent call to Propagate.
+	Collided bool
+}
+
+// julianDay converts a time.Time into a julian day, consistent with
+// data.J2000.
+func julianDay(t time.Time) float64 {
+	const unixEpochJD = 2440587.5
+	return float64(t.UnixNano())/86400e9 + unixEpochJD
+}
+
+// Propagate integrates from start to end, sending each accepted step's
+// State to histChan. histChan is closed when Propagate returns, whether
+// because it reached end or because Collided became true.
+func (p *Propagator) Propagate(start, end time.Time, histChan chan<- State) {
+	defer close(histChan)
+
+	p.Collided = false
+	t := julianDay(start)
+	tEnd := julianDay(end)
+	dt := initialStepDays
+
+	for t < tEnd {
+		if dt > tEnd-t {
+			dt = tEnd - t
+		}
+
+		next, used, suggested := dp45Step(p.Forces, t, dt, p.Tol, p.State)
+		p.State = next
+		t += used
+		dt = suggested
+
+		for _, f := range p.Forces {
+			if d, ok := f.(Depletable); ok {
+				d.Deplete(used)
+			}
+		}
+
+		p.patchSoI(t)
+		if p.checkCollision() {
+			p.Collided = true
+			histChan <- p.State
+			return
+		}
+
+		histChan <- p.State
+	}
+}
+
+// checkCollision reports whether State has reached the current Parent's
+// surface.
+func (p *Propagator) checkCollision() bool {
+	return length(p.State.Pos) <= p.Parent.Radius()
+}
+
+// patchSoI re-parents State, and swaps in the matching central-gravity
+// Force, whenever the ship leaves its current parent's sphere of
+// influence or enters a satellite's.
+func (p *Propagator) patchSoI(t float64) {
+	if parent := p.Parent.Parent(); parent != nil && length(p.State.Pos) > p.Parent.SoiRadius() {
+		p.reparent(p.Parent, parent, t, 1)
+		return
+	}
+
+	for _, sat := range p.Parent.Satellites() {
+		satPos2D := sat.PositionAt(t)
+		satPos := data.Vector3D{X: satPos2D.X, Y: satPos2D.Y}
+		if length(sub(p.State.Pos, satPos)) <= sat.SoiRadius() {
+			p.reparent(p.Parent, sat, t, -1)
+			return
+		}
+	}
+}
+
+// reparent re-expresses State relative to newParent instead of oldParent,
+// and swaps the first central-gravity Force (if any) to newParent's.
+// sign is +1 when ascending to oldParent's parent, -1 when descending
+// into one of oldParent's satellites.
+func (p *Propagator) reparent(oldParent, newParent data.Orbitable, t float64, sign float64) {
+	var rel data.Orbitable
+	if sign > 0 {
+		rel = oldParent
+	} else {
+		rel = newParent
+	}
+
+	const dt = 1e-3 // days, for a central-difference velocity estimate
+	pos1 := rel.PositionAt(t - dt/2)
+	pos2 := rel.PositionAt(t + dt/2)
+	relPos := data.Vector3D{X: rel.PositionAt(t).X, Y: rel.PositionAt(t).Y}
+	relVel := data.Vector3D{X: (pos2.X - pos1.X) / dt, Y: (pos2.Y - pos1.Y) / dt}
+
+	p.State.Pos = add(p.State.Pos, scale(relPos, sign))
+	p.State.Vel = add(p.State.Vel, scale(relVel, sign))
+	p.Parent = newParent
+
+	for i, f := range p.Forces {
+		if g, ok := f.(PointMassGravity); ok && g.PosAt == nil {
+			p.Forces[i] = NewCentralGravity(newParent)
+			return
+		}
+	}
+}
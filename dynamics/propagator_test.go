@@ -0,0 +1,56 @@
+package dynamics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/chippydip/spacegame/data"
+)
+
+// TestPropagateCircularOrbit runs Propagate end-to-end on a low-earth
+// circular orbit under point-mass gravity alone, and checks that the
+// radius stays put (as a circular orbit should) rather than panicking or
+// drifting wildly, which is what a broken integrator would do.
+func TestPropagateCircularOrbit(t *testing.T) {
+	const earthMassKg = 5.97219e24
+	const earthRadiusAU = 6378135.0 / auMeters
+
+	earth := data.NewBody(data.Planet, "Earth", earthRadiusAU, earthMassKg, data.NewOrbit(0, 0, 0, 0, 0))
+	earth.NewSystem(nil)
+
+	const rAU = 6778000.0 / auMeters // ~400km altitude
+	v := math.Sqrt(gmAUday2(earth.GM()) / rAU)
+
+	p := &Propagator{
+		Forces: []Force{NewCentralGravity(earth)},
+		Parent: earth,
+		State:  State{Pos: data.Vector3D{X: rAU}, Vel: data.Vector3D{Y: v}},
+		Tol:    1e-9,
+	}
+
+	hist := make(chan State, 256)
+	start := time.Unix(0, 0)
+	end := start.Add(2 * time.Hour) // a bit more than one ~90 minute LEO period
+
+	p.Propagate(start, end, hist)
+
+	var last State
+	count := 0
+	for s := range hist {
+		last = s
+		count++
+	}
+
+	if count == 0 {
+		t.Fatal("Propagate produced no states")
+	}
+	if p.Collided {
+		t.Fatal("circular LEO orbit should not have collided with Earth")
+	}
+
+	r := length(last.Pos)
+	if math.Abs(r-rAU)/rAU > 0.01 {
+		t.Fatalf("radius drifted too much after 2h: got %v AU, want ~%v AU", r, rAU)
+	}
+}
@@ -0,0 +1,12 @@
+package dynamics
+
+// Spacecraft tracks the mass properties of a numerically-propagated Ship:
+// its dry mass plus however much fuel it has left. Thrust forces read and
+// deplete FuelKg as the Propagator advances.
+type Spacecraft struct {
+	DryMassKg float64
+	FuelKg    float64
+}
+
+// MassKg is the spacecraft's current total mass.
+func (s *Spacecraft) MassKg() float64 { return s.DryMassKg + s.FuelKg }
@@ -0,0 +1,34 @@
+// Package dynamics numerically propagates a Ship's trajectory under a
+// pluggable set of forces, rather than following a fixed Keplerian orbit.
+package dynamics
+
+import "github.com/chippydip/spacegame/data"
+
+// State is a cartesian position/velocity state vector, in AU and AU/day,
+// relative to whichever body is currently the Propagator's Parent.
+type State struct {
+	Pos data.Vector3D
+	Vel data.Vector3D
+}
+
+func addScaled(s, ds State, f float64) State {
+	return State{
+		Pos: add(s.Pos, scale(ds.Pos, f)),
+		Vel: add(s.Vel, scale(ds.Vel, f)),
+	}
+}
+
+// auPerDay2FromSI converts an SI acceleration (m/s^2) into AU/day^2.
+func auPerDay2FromSI(a float64) float64 {
+	const secondsPerDay = 86400.0
+	return a * secondsPerDay * secondsPerDay / auMeters
+}
+
+// gmAUday2 converts a gravitational parameter (m^3/s^2) into AU^3/day^2.
+func gmAUday2(gmSI float64) float64 {
+	const secondsPerDay = 86400.0
+	return gmSI * secondsPerDay * secondsPerDay / (auMeters * auMeters * auMeters)
+}
+
+// auMeters is the number of meters in one astronomical unit.
+const auMeters = 1.495978707e11
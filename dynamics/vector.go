@@ -0,0 +1,23 @@
+package dynamics
+
+import (
+	"math"
+
+	"github.com/chippydip/spacegame/data"
+)
+
+func add(a, b data.Vector3D) data.Vector3D {
+	return data.Vector3D{X: a.X + b.X, Y: a.Y + b.Y, Z: a.Z + b.Z}
+}
+
+func sub(a, b data.Vector3D) data.Vector3D {
+	return data.Vector3D{X: a.X - b.X, Y: a.Y - b.Y, Z: a.Z - b.Z}
+}
+
+func scale(a data.Vector3D, f float64) data.Vector3D {
+	return data.Vector3D{X: a.X * f, Y: a.Y * f, Z: a.Z * f}
+}
+
+func length(a data.Vector3D) float64 {
+	return math.Sqrt(a.X*a.X + a.Y*a.Y + a.Z*a.Z)
+}
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/http"
-	"time"
+	"os"
+	"os/signal"
 
 	"github.com/gorilla/websocket"
 	"github.com/skratchdot/open-golang/open"
@@ -23,9 +25,23 @@ func main() {
 		panic(err)
 	}
 	listener = l
+
+	server := &http.Server{}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		fmt.Println("shutting down")
+		connections.closeAll()
+		server.Close()
+	}()
+
 	fmt.Println("listening on", listener.Addr().String())
 	open.Run("http://" + listener.Addr().String())
-	panic(http.Serve(listener, nil))
+	if err := server.Serve(listener); err != http.ErrServerClosed {
+		panic(err)
+	}
 }
 
 var upgrader = websocket.Upgrader{
@@ -33,34 +49,19 @@ var upgrader = websocket.Upgrader{
 	WriteBufferSize: 1024,
 }
 
+// ws upgrades the request to a websocket and drives the simulation
+// protocol (load/subscribe/setTime/timeWarp/snapshot) for that connection
+// until it closes.
 func ws(w http.ResponseWriter, r *http.Request) {
-	defer func() {
-		fmt.Println("exiting")
-		//listener.Close()
-	}()
-
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
 
-	for {
-		msgType, msg, err := conn.ReadMessage()
-		if err != nil {
-			fmt.Println(err)
-			return
-		}
-		if string(msg) == "ping" {
-			fmt.Println("ping")
-			time.Sleep(2 * time.Second)
-			err = conn.WriteMessage(msgType, []byte("pong"))
-			if err != nil {
-				fmt.Println(err)
-				return
-			}
-		} else {
-			fmt.Println(string(msg))
-		}
-	}
+	c := newClientConn(conn)
+	connections.add(c)
+	defer connections.remove(c)
+
+	c.run()
 }
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/chippydip/spacegame/data"
+)
+
+// clock is the single shared simulation clock. All connections read the
+// same jd/warp pair so that time-warping from one client is reflected in
+// every other client's stream.
+type clock struct {
+	mu   sync.Mutex
+	jd   float64
+	warp float64 // simulated seconds per real second
+	last time.Time
+}
+
+func newClock(jd0 float64) *clock {
+	return &clock{jd: jd0, warp: 1, last: time.Now()}
+}
+
+func (c *clock) advanceLocked() {
+	now := time.Now()
+	c.jd += now.Sub(c.last).Seconds() * c.warp / 86400
+	c.last = now
+}
+
+// JD returns the current simulation time, advancing it for elapsed real
+// time at the current warp factor first.
+func (c *clock) JD() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.advanceLocked()
+	return c.jd
+}
+
+func (c *clock) SetJD(jd float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.advanceLocked()
+	c.jd = jd
+}
+
+func (c *clock) SetWarp(factor float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.advanceLocked()
+	c.warp = factor
+}
+
+// simClock is the clock shared by every websocket connection.
+var simClock = newClock(data.J2000)
+
+// systemMu guards activeSystem, which is read from every connection's
+// run goroutine on each pushed frame and written whenever a client sends
+// a "load" command.
+var (
+	systemMu     sync.RWMutex
+	activeSystem data.System // nil until something loads one
+)
+
+// setActiveSystem installs the System to be simulated, replacing
+// whatever was loaded before. Safe to call concurrently with
+// getActiveSystem.
+func setActiveSystem(s data.System) {
+	systemMu.Lock()
+	defer systemMu.Unlock()
+	activeSystem = s
+}
+
+// getActiveSystem returns the System currently being simulated, or nil
+// if nothing has loaded one yet.
+func getActiveSystem() data.System {
+	systemMu.RLock()
+	defer systemMu.RUnlock()
+	return activeSystem
+}
+
+// defaultSystem builds the built-in Sun-Earth System used by the "load"
+// op. It's a placeholder catalog until a richer loader (a real
+// planetary database, TLE ingestion, etc.) replaces it.
+func defaultSystem() data.System {
+	const auKm = 149597870.7
+	const sunMassKg = 1.98847e30
+	const earthMassKg = 5.97219e24
+	const earthAU = 1.00000011
+	const earthE = 0.0167086
+	const earthN = 2 * math.Pi / 365.256363 // rad/day
+
+	sun := data.NewBody(data.Star, "Sun", 696000.0/auKm, sunMassKg, data.NewOrbit(0, 0, 0, 0, 0))
+	earth := data.NewBody(data.Planet, "Earth", 6378.135/auKm, earthMassKg,
+		data.NewOrbit(earthAU, earthE, 0, 0, earthN))
+
+	return sun.NewSystem([]data.Orbitable{earth})
+}
+
+// allBodies flattens a System into every Orbitable it (transitively)
+// contains.
+func allBodies(o data.Orbitable) []data.Orbitable {
+	if o == nil {
+		return nil
+	}
+	bodies := []data.Orbitable{o}
+	for _, s := range o.Satellites() {
+		bodies = append(bodies, allBodies(s)...)
+	}
+	return bodies
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	pingPeriod = 30 * time.Second
+	pongWait   = pingPeriod * 3 / 2
+	writeWait  = 10 * time.Second
+)
+
+// clientMessage is the JSON shape of every command a client can send.
+// Fields not relevant to a given op are simply omitted.
+type clientMessage struct {
+	Op     string   `json:"op"`
+	Bodies []string `json:"bodies,omitempty"` // subscribe: body names, or ["*"] for all
+	RateHz float64  `json:"rateHz,omitempty"` // subscribe: frames per second
+	JD     float64  `json:"jd,omitempty"`     // setTime
+	Factor float64  `json:"factor,omitempty"` // timeWarp
+}
+
+// bodyPosition is one body's position within a server-pushed state frame.
+type bodyPosition struct {
+	Name string  `json:"name"`
+	X    float64 `json:"x"`
+	Y    float64 `json:"y"`
+}
+
+// stateMessage is a server-pushed snapshot of subscribed body positions.
+type stateMessage struct {
+	Op        string         `json:"op"`
+	JD        float64        `json:"jd"`
+	Positions []bodyPosition `json:"positions"`
+}
+
+// registry tracks every live connection so the server can close them all
+// on shutdown.
+type registry struct {
+	mu    sync.Mutex
+	conns map[*clientConn]struct{}
+}
+
+var connections = &registry{conns: map[*clientConn]struct{}{}}
+
+func (r *registry) add(c *clientConn) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.conns[c] = struct{}{}
+}
+
+func (r *registry) remove(c *clientConn) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.conns, c)
+}
+
+func (r *registry) closeAll() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for c := range r.conns {
+		c.close()
+	}
+}
+
+// clientConn wraps one websocket connection. All writes to conn happen on
+// the run goroutine; reads happen on a dedicated reader goroutine that
+// forwards decoded commands (or a close signal) back to run.
+type clientConn struct {
+	conn *websocket.Conn
+
+	cmds chan clientMessage
+	done chan struct{}
+
+	bodies []string // subscribed body names, or ["*"] for everything
+	rateHz float64
+}
+
+func newClientConn(conn *websocket.Conn) *clientConn {
+	return &clientConn{
+		conn: conn,
+		cmds: make(chan clientMessage, 8),
+		done: make(chan struct{}),
+	}
+}
+
+// close requests that run stop; safe to call more than once.
+func (c *clientConn) close() {
+	select {
+	case <-c.done:
+	default:
+		close(c.done)
+	}
+}
+
+// readLoop decodes incoming client messages and forwards them to cmds. It
+// returns (closing c.done) when the connection errors or closes.
+func (c *clientConn) readLoop() {
+	defer c.close()
+
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+
+	for {
+		_, raw, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var msg clientMessage
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			fmt.Println("websocket: bad message:", err)
+			continue
+		}
+
+		select {
+		case c.cmds <- msg:
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// run is the per-connection event loop: it applies client commands,
+// streams state frames at the subscribed rate, and sends keepalive pings.
+// It owns the only writer to c.conn, per gorilla/websocket's requirement
+// that a connection have at most one concurrent writer.
+func (c *clientConn) run() {
+	defer c.conn.Close()
+
+	go c.readLoop()
+
+	ping := time.NewTicker(pingPeriod)
+	defer ping.Stop()
+
+	frames := time.NewTicker(time.Hour) // reset once a rate is subscribed
+	frames.Stop()
+	defer frames.Stop()
+
+	for {
+		select {
+		case msg := <-c.cmds:
+			c.handle(msg, frames)
+
+		case <-frames.C:
+			c.sendState(c.conn)
+
+		case <-ping.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+
+		case <-c.done:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			return
+		}
+	}
+}
+
+func (c *clientConn) handle(msg clientMessage, frames *time.Ticker) {
+	switch msg.Op {
+	case "subscribe":
+		c.bodies = msg.Bodies
+		c.rateHz = msg.RateHz
+		if c.rateHz <= 0 {
+			frames.Stop()
+			return
+		}
+		frames.Reset(time.Duration(float64(time.Second) / c.rateHz))
+
+	case "setTime":
+		simClock.SetJD(msg.JD)
+
+	case "timeWarp":
+		simClock.SetWarp(msg.Factor)
+
+	case "snapshot":
+		c.sendState(c.conn)
+
+	case "load":
+		setActiveSystem(defaultSystem())
+
+	default:
+		fmt.Println("websocket: unknown op:", msg.Op)
+	}
+}
+
+// sendState writes one state frame for the connection's current
+// subscription (or, for an explicit snapshot request, whatever it's
+// currently subscribed to - possibly nothing).
+func (c *clientConn) sendState(conn *websocket.Conn) {
+	jd := simClock.JD()
+
+	frame := stateMessage{Op: "state", JD: jd}
+	for _, body := range allBodies(getActiveSystem()) {
+		if !c.subscribed(body.Name()) {
+			continue
+		}
+		pos := body.PositionAt(jd)
+		frame.Positions = append(frame.Positions, bodyPosition{body.Name(), pos.X, pos.Y})
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(writeWait))
+	if err := conn.WriteJSON(frame); err != nil {
+		c.close()
+	}
+}
+
+func (c *clientConn) subscribed(name string) bool {
+	for _, b := range c.bodies {
+		if b == "*" || b == name {
+			return true
+		}
+	}
+	return false
+}